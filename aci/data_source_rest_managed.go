@@ -0,0 +1,118 @@
+package aci
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ciscoecosystem/aci-go-client/client"
+	"github.com/ciscoecosystem/aci-go-client/container"
+	"github.com/ciscoecosystem/aci-go-client/models"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceAciRestManaged() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAciRestManagedRead,
+
+		Schema: map[string]*schema.Schema{
+			"dn": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"class_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"content": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+			"children": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rn": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"class_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"content": &schema.Schema{
+							Type:     schema.TypeMap,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func flattenRestManagedContent(cont *container.Container, className string) map[string]interface{} {
+	content := make(map[string]interface{})
+	attributes := cont.S(className, "attributes")
+	children, err := attributes.ChildrenMap()
+	if err != nil {
+		return content
+	}
+	for key := range children {
+		content[key] = models.StripQuotes(models.StripSquareBrackets(attributes.S(key).String()))
+	}
+	return content
+}
+
+func dataSourceAciRestManagedRead(d *schema.ResourceData, m interface{}) error {
+	dn := d.Get("dn").(string)
+	className := d.Get("class_name").(string)
+
+	log.Printf("[DEBUG] %s: Beginning Read", dn)
+
+	aciClient := m.(*client.Client)
+	path := getPath(dn) + "?rsp-subtree=full"
+
+	req, err := aciClient.MakeRestRequest("GET", path, nil, true)
+	if err != nil {
+		return err
+	}
+
+	cont, _, err := aciClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if cont.S("imdata").Index(0).String() == "{}" {
+		return fmt.Errorf("no object found with dn %s", dn)
+	}
+
+	err = client.CheckForErrors(cont, "GET", false)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(dn)
+	d.Set("class_name", className)
+	d.Set("content", flattenRestManagedContent(cont.S("imdata").Index(0), className))
+
+	children := make([]map[string]interface{}, 0)
+	for _, childCont := range cont.S("imdata").Index(0).S(className, "children").Children() {
+		childMap, err := childCont.ChildrenMap()
+		if err != nil {
+			continue
+		}
+		for childClass, childData := range childMap {
+			children = append(children, map[string]interface{}{
+				"rn":         models.StripQuotes(childData.S("attributes", "rn").String()),
+				"class_name": childClass,
+				"content":    flattenRestManagedContent(childCont, childClass),
+			})
+		}
+	}
+	d.Set("children", children)
+
+	log.Printf("[DEBUG] %s: Read finished successfully", dn)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package aci
+
+import (
+	"testing"
+
+	"github.com/ciscoecosystem/aci-go-client/container"
+)
+
+func TestFlattenRestManagedContentStripsQuotesAndBrackets(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"fvTenant":{"attributes":{"name":"T1","descr":"[quoted]"}}}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+
+	content := flattenRestManagedContent(cont, "fvTenant")
+	if content["name"] != "T1" {
+		t.Fatalf("expected name T1, got %v", content["name"])
+	}
+	if content["descr"] != "quoted" {
+		t.Fatalf("expected descr to have brackets stripped, got %v", content["descr"])
+	}
+}
+
+func TestFlattenRestManagedContentNoAttributes(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"fvTenant":{}}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+
+	content := flattenRestManagedContent(cont, "fvTenant")
+	if len(content) != 0 {
+		t.Fatalf("expected no content for a class with no attributes, got %+v", content)
+	}
+}
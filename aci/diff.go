@@ -0,0 +1,37 @@
+package aci
+
+// DiffMode selects how resourceAciRestManaged decides whether a configured
+// attribute has drifted from what APIC actually persisted.
+type DiffMode string
+
+const (
+	// DiffModeClient compares every configured attribute against whatever
+	// value APIC reports for it, including values APIC injected by default.
+	// This is the original behavior and can show drift for attributes the
+	// user never set, due to type coercion (e.g. "1" vs "true") or APIC
+	// filling in a default.
+	DiffModeClient DiffMode = "client"
+
+	// DiffModeServer asks APIC to report only attributes that differ from
+	// its defaults (rsp-prop-include=config-only) and reconciles state from
+	// that reduced set, so the plan reflects what was actually persisted
+	// rather than every default APIC fills in.
+	DiffModeServer DiffMode = "server"
+)
+
+var defaultDiffMode = DiffModeClient
+
+// SetDiffMode overrides the package-wide diff mode used by
+// resourceAciRestManaged. It is called once from the provider's
+// ConfigureFunc based on the top-level diff_mode argument.
+func SetDiffMode(mode DiffMode) {
+	if mode == DiffModeServer {
+		defaultDiffMode = DiffModeServer
+		return
+	}
+	defaultDiffMode = DiffModeClient
+}
+
+// configOnlyQueryParam is appended to GET requests in server diff mode so
+// APIC only returns attributes that differ from its defaults.
+const configOnlyQueryParam = "rsp-prop-include=config-only"
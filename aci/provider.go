@@ -0,0 +1,107 @@
+package aci
+
+import (
+	"time"
+
+	"github.com/ciscoecosystem/aci-go-client/client"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Provider returns the aci Terraform provider.
+func Provider() terraform.ResourceProvider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"url": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"insecure": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"max_retries": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  defaultRetryPolicy.MaxRetries,
+			},
+			"retry_min_delay": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  int(defaultRetryPolicy.MinDelay / time.Second),
+			},
+			"retry_max_delay": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  int(defaultRetryPolicy.MaxDelay / time.Second),
+			},
+			"retryable_status_codes": &schema.Schema{
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+				Optional: true,
+			},
+			"diff_mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(defaultDiffMode),
+			},
+			"subscription_mode": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  websocketModeEnabled,
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"aci_rest_managed":        resourceAciRestManaged(),
+			"aci_rest_managed_bundle": resourceAciRestManagedBundle(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"aci_rest_managed": dataSourceAciRestManaged(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	aciClient := client.GetClient(
+		d.Get("url").(string),
+		d.Get("username").(string),
+		client.Password(d.Get("password").(string)),
+		client.Insecure(d.Get("insecure").(bool)),
+	)
+
+	policy := RetryPolicy{
+		MaxRetries: d.Get("max_retries").(int),
+		MinDelay:   time.Duration(d.Get("retry_min_delay").(int)) * time.Second,
+		MaxDelay:   time.Duration(d.Get("retry_max_delay").(int)) * time.Second,
+	}
+	if codes := d.Get("retryable_status_codes").([]interface{}); len(codes) > 0 {
+		statusCodes := make([]int, len(codes))
+		for i, code := range codes {
+			statusCodes[i] = code.(int)
+		}
+		policy.RetryableStatusCodes = statusCodes
+	} else {
+		policy.RetryableStatusCodes = defaultRetryPolicy.RetryableStatusCodes
+	}
+	SetRetryPolicy(policy)
+
+	SetDiffMode(DiffMode(d.Get("diff_mode").(string)))
+
+	SetWebsocketMode(d.Get("subscription_mode").(bool))
+
+	return aciClient, nil
+}
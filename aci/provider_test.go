@@ -0,0 +1,23 @@
+package aci
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestProviderRegistersRestManagedDataSource(t *testing.T) {
+	p := Provider().(*schema.Provider)
+	if p.DataSourcesMap["aci_rest_managed"] == nil {
+		t.Fatal("expected aci_rest_managed data source to be registered")
+	}
+	if p.ResourcesMap["aci_rest_managed"] == nil {
+		t.Fatal("expected aci_rest_managed resource to be registered")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+		t.Fatalf("provider schema failed validation: %s", err)
+	}
+}
@@ -2,6 +2,8 @@ package aci
 
 import (
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/ciscoecosystem/aci-go-client/client"
 	"github.com/ciscoecosystem/aci-go-client/container"
@@ -9,6 +11,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
+// Retries is kept for backwards compatibility with callers that still refer
+// to it directly; resourceAciRestManaged itself now retries according to
+// defaultRetryPolicy, which defaults to the same value.
 const Retries = 2
 
 func resourceAciRestManaged() *schema.Resource {
@@ -41,6 +46,37 @@ func resourceAciRestManaged() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
 			},
+			"child": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rn": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"class_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"content": &schema.Schema{
+							Type:     schema.TypeMap,
+							Optional: true,
+							Computed: true,
+						},
+						"state_ignore_attributes": &schema.Schema{
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Optional: true,
+						},
+						"child_delete_on_removal": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -71,59 +107,202 @@ func getAciRestManaged(d *schema.ResourceData, c *container.Container) error {
 		}
 		if ignore_found {
 			newContent[key] = value
-		} else {
-			newContent[key] = models.StripQuotes(models.StripSquareBrackets(c.Search("imdata", className, "attributes", key).String()))
+			continue
 		}
+
+		attr := c.Search("imdata", className, "attributes", key)
+		newContent[key] = models.StripQuotes(models.StripSquareBrackets(attr.String()))
 	}
 	d.Set("content", newContent)
+
+	children := readRestManagedChildren(d)
+	newChildren := make([]map[string]interface{}, len(children))
+	for i, ch := range children {
+		newChildren[i] = map[string]interface{}{
+			"rn":                      ch.Rn,
+			"class_name":              ch.ClassName,
+			"content":                 reconcileChild(c, className, ch),
+			"state_ignore_attributes": ch.IgnoreAttrs,
+			"child_delete_on_removal": ch.DeleteOnRemoval,
+		}
+	}
+	d.Set("child", newChildren)
+
+	return nil
+}
+
+// restManagedChild is the parsed form of one "child" block of
+// resourceAciRestManaged.
+type restManagedChild struct {
+	Rn              string
+	ClassName       string
+	Content         map[string]interface{}
+	IgnoreAttrs     []string
+	DeleteOnRemoval bool
+}
+
+func readRestManagedChildren(d *schema.ResourceData) []restManagedChild {
+	return readRestManagedChildrenFromList(d.Get("child").([]interface{}))
+}
+
+func readRestManagedChildrenFromList(raw []interface{}) []restManagedChild {
+	children := make([]restManagedChild, 0, len(raw))
+	for _, v := range raw {
+		m := v.(map[string]interface{})
+		children = append(children, restManagedChild{
+			Rn:              m["rn"].(string),
+			ClassName:       m["class_name"].(string),
+			Content:         toStrMap(m["content"].(map[string]interface{})),
+			IgnoreAttrs:     toStringList(m["state_ignore_attributes"].(*schema.Set).List()),
+			DeleteOnRemoval: m["child_delete_on_removal"].(bool),
+		})
+	}
+	return children
+}
+
+// deleteRestManagedChild removes a single child MO that was dropped from
+// configuration, addressed as "<parent dn>/<child rn>".
+func deleteRestManagedChild(d *schema.ResourceData, m interface{}, ch restManagedChild) error {
+	aciClient := m.(*client.Client)
+	dn := d.Id() + "/" + ch.Rn
+	return aciClient.DeleteByDn(dn, ch.ClassName)
+}
+
+// composeChildrenPayload nests each declared child under cont's
+// "<className>.children" array so that a single RestPost creates or updates
+// the parent MO together with its children.
+func composeChildrenPayload(cont *container.Container, className string, children []restManagedChild) error {
+	for _, ch := range children {
+		childCont, err := preparePayload(ch.ClassName, ch.Content)
+		if err != nil {
+			return err
+		}
+		if _, err := childCont.Set(ch.Rn, ch.ClassName, "attributes", "rn"); err != nil {
+			return err
+		}
+		if _, err := cont.ArrayAppend(childCont.Data(), className, "children"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// reconcileChild walks the children returned for className under imdata and
+// picks out the one matching ch.Rn, applying state_ignore_attributes the
+// same way getAciRestManaged does for the parent's content. Children that are
+// not declared in configuration are left untouched, as only declared
+// children are tracked in state.
+func reconcileChild(c *container.Container, className string, ch restManagedChild) map[string]interface{} {
+	newContent := make(map[string]interface{})
+	for _, childCont := range c.S("imdata").Index(0).S(className, "children").Children() {
+		childMap, err := childCont.ChildrenMap()
+		if err != nil {
+			continue
+		}
+		attrs, ok := childMap[ch.ClassName]
+		if !ok {
+			continue
+		}
+		if models.StripQuotes(attrs.S("attributes", "rn").String()) != ch.Rn {
+			continue
+		}
+		for key, value := range ch.Content {
+			ignoreFound := false
+			for _, ignoreAttr := range ch.IgnoreAttrs {
+				if ignoreAttr == key {
+					ignoreFound = true
+					break
+				}
+			}
+			if ignoreFound {
+				newContent[key] = value
+				continue
+			}
+
+			attr := attrs.S("attributes", key)
+			newContent[key] = models.StripQuotes(models.StripSquareBrackets(attr.String()))
+		}
+		break
+	}
+	return newContent
+}
+
+// removedChildren returns children present in old but absent from new that
+// have child_delete_on_removal set, so the caller can explicitly delete them
+// instead of silently orphaning them under their (unchanged) parent.
+func removedChildren(old, new []restManagedChild) []restManagedChild {
+	stillPresent := make(map[string]bool, len(new))
+	for _, ch := range new {
+		stillPresent[ch.ClassName+"|"+ch.Rn] = true
+	}
+
+	var removed []restManagedChild
+	for _, ch := range old {
+		if ch.DeleteOnRemoval && !stillPresent[ch.ClassName+"|"+ch.Rn] {
+			removed = append(removed, ch)
+		}
+	}
+	return removed
+}
+
 func resourceAciRestManagedCreate(d *schema.ResourceData, m interface{}) error {
+	policy := defaultRetryPolicy
 	for attempts := 0; ; attempts++ {
-		cont, err := RestPost(d, m)
+		cont, statusCode, wait, err := RestPost(d, m)
 		if err != nil {
-			if attempts >= Retries {
+			if !shouldRetry(err, statusCode, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to create object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to create object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, wait))
+			continue
 		}
 
 		err = getAciRestManaged(d, cont)
 		if err != nil {
-			if attempts >= Retries {
+			if !shouldRetry(err, 0, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to decode response after creating object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to decode response after creating object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, 0))
+			continue
 		}
 		return nil
 	}
 }
 
 func resourceAciRestManagedUpdate(d *schema.ResourceData, m interface{}) error {
+	if d.HasChange("child") {
+		oldRaw, newRaw := d.GetChange("child")
+		old := readRestManagedChildrenFromList(oldRaw.([]interface{}))
+		new := readRestManagedChildrenFromList(newRaw.([]interface{}))
+		for _, ch := range removedChildren(old, new) {
+			if err := deleteRestManagedChild(d, m, ch); err != nil {
+				return err
+			}
+		}
+	}
+
+	policy := defaultRetryPolicy
 	for attempts := 0; ; attempts++ {
-		cont, err := RestPost(d, m)
+		cont, statusCode, wait, err := RestPost(d, m)
 		if err != nil {
-			if attempts >= Retries {
+			if !shouldRetry(err, statusCode, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to update object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to update object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, wait))
+			continue
 		}
 
 		err = getAciRestManaged(d, cont)
 		if err != nil {
-			if attempts >= Retries {
+			if !shouldRetry(err, 0, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to decode response after updating object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to decode response after updating object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, 0))
+			continue
 		}
 		return nil
 	}
@@ -132,15 +311,30 @@ func resourceAciRestManagedUpdate(d *schema.ResourceData, m interface{}) error {
 func resourceAciRestManagedRead(d *schema.ResourceData, m interface{}) error {
 	log.Printf("[DEBUG] %s: Beginning Read", d.Id())
 
+	if websocketModeEnabled {
+		cont, err := getSubscriptionManager(m.(*client.Client)).read(d.Get("dn").(string))
+		if err == nil {
+			// A nil container with no error means the dn does not exist, the
+			// same convention RestGet uses for an empty imdata.
+			if cont == nil {
+				d.SetId("")
+				return nil
+			}
+			return getAciRestManaged(d, cont)
+		}
+		log.Printf("[ERROR] aci_rest_managed falling back to REST GET for %s: %s", d.Id(), err)
+	}
+
+	policy := defaultRetryPolicy
 	for attempts := 0; ; attempts++ {
-		cont, err := RestGet(d, m)
+		cont, statusCode, wait, err := RestGet(d, m)
 		if err != nil {
-			if attempts >= Retries {
+			if !shouldRetry(err, statusCode, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to read object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to read object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, wait))
+			continue
 		}
 
 		// Check if we received an empty response without errors -> object has been deleted
@@ -151,17 +345,15 @@ func resourceAciRestManagedRead(d *schema.ResourceData, m interface{}) error {
 
 		err = getAciRestManaged(d, cont)
 		if err != nil {
-			if attempts >= Retries {
+			if !shouldRetry(err, 0, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to decode response after reading object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to decode response after reading object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, 0))
+			continue
 		}
 
-		if err == nil {
-			break
-		}
+		break
 	}
 
 	log.Printf("[DEBUG] %s: Read finished successfully", d.Id())
@@ -175,16 +367,17 @@ func resourceAciRestManagedDelete(d *schema.ResourceData, m interface{}) error {
 	aciClient := m.(*client.Client)
 	dn := d.Id()
 	className := d.Get("class_name").(string)
+	policy := defaultRetryPolicy
 	var err error
 	for attempts := 0; ; attempts++ {
 		err = aciClient.DeleteByDn(dn, className)
-		if err != nil && attempts >= Retries {
-			if attempts >= Retries {
+		if err != nil {
+			if !shouldRetry(err, 0, attempts, policy) {
 				return err
-			} else {
-				log.Printf("[ERROR] Failed to delete object: %s, retries: %v", err, attempts)
-				continue
 			}
+			log.Printf("[ERROR] Failed to delete object: %s, retries: %v", err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, 0))
+			continue
 		}
 		break
 	}
@@ -195,32 +388,46 @@ func resourceAciRestManagedDelete(d *schema.ResourceData, m interface{}) error {
 	return err
 }
 
-func RestGet(d *schema.ResourceData, m interface{}) (*container.Container, error) {
+// statusCodeOf extracts the HTTP status code from an APIC response, if any,
+// so retry decisions can take 429/5xx responses into account.
+func statusCodeOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func RestGet(d *schema.ResourceData, m interface{}) (*container.Container, int, time.Duration, error) {
 	aciClient := m.(*client.Client)
 	path := getPath(d.Get("dn").(string))
+	if defaultDiffMode == DiffModeServer {
+		path += "?" + configOnlyQueryParam
+	}
 
 	req, err := aciClient.MakeRestRequest("GET", path, nil, true)
 	if err != nil {
-		return nil, err
+		return nil, 0, 0, err
 	}
 
-	respCont, _, err := aciClient.Do(req)
+	respCont, resp, err := aciClient.Do(req)
+	statusCode := statusCodeOf(resp)
+	wait := retryAfter(resp)
 	if err != nil {
-		return respCont, err
+		return respCont, statusCode, wait, err
 	}
 
 	if respCont.S("imdata").Index(0).String() == "{}" {
-		return nil, nil
+		return nil, statusCode, wait, nil
 	}
 
 	err = client.CheckForErrors(respCont, "GET", false)
 	if err != nil {
-		return respCont, err
+		return respCont, statusCode, wait, err
 	}
-	return respCont, nil
+	return respCont, statusCode, wait, nil
 }
 
-func RestPost(d *schema.ResourceData, m interface{}) (*container.Container, error) {
+func RestPost(d *schema.ResourceData, m interface{}) (*container.Container, int, time.Duration, error) {
 	aciClient := m.(*client.Client)
 	path := getPath(d.Get("dn").(string))
 	var cont *container.Container
@@ -233,21 +440,30 @@ func RestPost(d *schema.ResourceData, m interface{}) (*container.Container, erro
 	className := d.Get("class_name").(string)
 	cont, err = preparePayload(className, contentStrMap)
 	if err != nil {
-		return nil, err
+		return nil, 0, 0, err
+	}
+
+	if err := composeChildrenPayload(cont, className, readRestManagedChildren(d)); err != nil {
+		return nil, 0, 0, err
 	}
 
 	req, err := aciClient.MakeRestRequest(method, path, cont, true)
 	if err != nil {
-		return nil, err
+		return nil, 0, 0, err
 	}
 
-	respCont, _, err := aciClient.Do(req)
+	respCont, resp, err := aciClient.Do(req)
+	statusCode := statusCodeOf(resp)
+	wait := retryAfter(resp)
 	if err != nil {
-		return respCont, err
+		return respCont, statusCode, wait, err
 	}
 	err = client.CheckForErrors(respCont, method, false)
 	if err != nil {
-		return respCont, err
+		return respCont, statusCode, wait, err
 	}
-	return cont, nil
+	// Reconcile state from what APIC actually persisted, not from the
+	// payload we sent: APIC can reject, coerce or default attributes, and
+	// the response is the only place that shows up.
+	return respCont, statusCode, wait, nil
 }
@@ -0,0 +1,358 @@
+package aci
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ciscoecosystem/aci-go-client/client"
+	"github.com/ciscoecosystem/aci-go-client/container"
+	"github.com/ciscoecosystem/aci-go-client/models"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceAciRestManagedBundle applies a set of aci_rest_managed-style
+// payloads as a single ACI transaction, so that dependent MOs are created
+// atomically and in the correct parent/child order instead of one REST
+// call per MO.
+func resourceAciRestManagedBundle() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAciRestManagedBundleCreate,
+		Update: resourceAciRestManagedBundleUpdate,
+		Read:   resourceAciRestManagedBundleRead,
+		Delete: resourceAciRestManagedBundleDelete,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"item": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"class_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"content": &schema.Schema{
+							Type:     schema.TypeMap,
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type bundleItem struct {
+	Dn        string
+	ClassName string
+	Content   map[string]interface{}
+}
+
+func bundleItemsFromResourceData(d *schema.ResourceData) []bundleItem {
+	return bundleItemsFromList(d.Get("item").([]interface{}))
+}
+
+func bundleItemsFromList(raw []interface{}) []bundleItem {
+	items := make([]bundleItem, 0, len(raw))
+	for _, v := range raw {
+		m := v.(map[string]interface{})
+		items = append(items, bundleItem{
+			Dn:        m["dn"].(string),
+			ClassName: m["class_name"].(string),
+			Content:   toStrMap(m["content"].(map[string]interface{})),
+		})
+	}
+	return items
+}
+
+// removedBundleItems returns items present in old but absent from new,
+// addressed by dn, so the caller can explicitly delete them instead of
+// merely dropping them from Terraform state while they stay orphaned in
+// APIC.
+func removedBundleItems(old, new []bundleItem) []bundleItem {
+	stillPresent := make(map[string]bool, len(new))
+	for _, it := range new {
+		stillPresent[it.Dn] = true
+	}
+
+	var removed []bundleItem
+	for _, it := range old {
+		if !stillPresent[it.Dn] {
+			removed = append(removed, it)
+		}
+	}
+	return removed
+}
+
+// bundleId derives a stable resource id from the set of managed dns, since a
+// bundle does not correspond to a single ACI object.
+func bundleId(items []bundleItem) string {
+	dns := make([]string, len(items))
+	for i, it := range items {
+		dns[i] = it.Dn
+	}
+	sort.Strings(dns)
+	sum := md5.Sum([]byte(strings.Join(dns, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// composeBundlePayload nests each item's payload under its parent based on dn
+// ancestry, wraps the result under "uni" and returns the composed transaction
+// body ready to be POSTed to /api/mo/uni.json.
+//
+// Only items whose parent dn is itself part of the bundle are nested as
+// children; an item's real ACI parent may not be included in the bundle at
+// all (e.g. adding MOs under an already-existing tenant), in which case it is
+// posted at its own absolute dn rather than being mis-nested under uni.
+func composeBundlePayload(items []bundleItem) (*container.Container, error) {
+	type node struct {
+		item        bundleItem
+		cont        *container.Container
+		childrenDns []string
+	}
+
+	nodes := make(map[string]*node, len(items))
+	for _, it := range items {
+		cont, err := preparePayload(it.ClassName, it.Content)
+		if err != nil {
+			return nil, err
+		}
+		nodes[it.Dn] = &node{item: it, cont: cont}
+	}
+
+	var roots []string
+	for _, it := range items {
+		parent := ""
+		for _, other := range items {
+			if other.Dn == it.Dn {
+				continue
+			}
+			if strings.HasPrefix(it.Dn, other.Dn+"/") && len(other.Dn) > len(parent) {
+				parent = other.Dn
+			}
+		}
+
+		n := nodes[it.Dn]
+		rn := strings.TrimPrefix(it.Dn, parent+"/")
+		if parent == "" || strings.Contains(rn, "/") {
+			// Either nothing else in the bundle is this item's ACI parent, or
+			// the closest match found above is an ancestor rather than the
+			// direct parent (an intermediate level is missing from the
+			// bundle), which would otherwise produce a multi-segment rn
+			// nested straight under that ancestor's children. Either way,
+			// address the item by its own absolute dn instead.
+			if _, err := n.cont.Set(it.Dn, it.ClassName, "attributes", "dn"); err != nil {
+				return nil, err
+			}
+			roots = append(roots, it.Dn)
+		} else {
+			if _, err := n.cont.Set(rn, it.ClassName, "attributes", "rn"); err != nil {
+				return nil, err
+			}
+			nodes[parent].childrenDns = append(nodes[parent].childrenDns, it.Dn)
+		}
+	}
+
+	var attach func(dn string) error
+	attach = func(dn string) error {
+		n := nodes[dn]
+		for _, childDn := range n.childrenDns {
+			if err := attach(childDn); err != nil {
+				return err
+			}
+			child := nodes[childDn]
+			if _, err := n.cont.ArrayAppend(child.cont.Data(), n.item.ClassName, "children"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	uni := container.New()
+	for _, rootDn := range roots {
+		if err := attach(rootDn); err != nil {
+			return nil, err
+		}
+		if _, err := uni.ArrayAppend(nodes[rootDn].cont.Data(), "uni", "children"); err != nil {
+			return nil, err
+		}
+	}
+	return uni, nil
+}
+
+func restPostBundle(payload *container.Container, m interface{}) (*container.Container, int, time.Duration, error) {
+	aciClient := m.(*client.Client)
+
+	req, err := aciClient.MakeRestRequest("POST", "/api/mo/uni.json", payload, true)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	respCont, resp, err := aciClient.Do(req)
+	statusCode := statusCodeOf(resp)
+	wait := retryAfter(resp)
+	if err != nil {
+		return respCont, statusCode, wait, err
+	}
+	err = client.CheckForErrors(respCont, "POST", false)
+	if err != nil {
+		return respCont, statusCode, wait, err
+	}
+	return respCont, statusCode, wait, nil
+}
+
+// applyBundleItems POSTs the composed transaction for items, retrying per
+// defaultRetryPolicy. action is used only for log messages.
+func applyBundleItems(items []bundleItem, m interface{}, action string) error {
+	payload, err := composeBundlePayload(items)
+	if err != nil {
+		return err
+	}
+
+	policy := defaultRetryPolicy
+	for attempts := 0; ; attempts++ {
+		_, statusCode, wait, err := restPostBundle(payload, m)
+		if err != nil {
+			if !shouldRetry(err, statusCode, attempts, policy) {
+				return err
+			}
+			log.Printf("[ERROR] Failed to %s: %s, retries: %v", action, err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, wait))
+			continue
+		}
+		return nil
+	}
+}
+
+// deletedBundleItems returns a copy of items with their content replaced by
+// an ACI deletion marker, ready to be posted as part of a transaction.
+func deletedBundleItems(items []bundleItem) []bundleItem {
+	deleted := make([]bundleItem, len(items))
+	for i, it := range items {
+		deleted[i] = bundleItem{
+			Dn:        it.Dn,
+			ClassName: it.ClassName,
+			Content:   map[string]interface{}{"status": "deleted"},
+		}
+	}
+	return deleted
+}
+
+func resourceAciRestManagedBundleApply(d *schema.ResourceData, m interface{}) error {
+	items := bundleItemsFromResourceData(d)
+	if err := applyBundleItems(items, m, "apply bundle"); err != nil {
+		return err
+	}
+
+	d.SetId(bundleId(items))
+	return nil
+}
+
+func resourceAciRestManagedBundleCreate(d *schema.ResourceData, m interface{}) error {
+	return resourceAciRestManagedBundleApply(d, m)
+}
+
+func resourceAciRestManagedBundleUpdate(d *schema.ResourceData, m interface{}) error {
+	if d.HasChange("item") {
+		oldRaw, newRaw := d.GetChange("item")
+		old := bundleItemsFromList(oldRaw.([]interface{}))
+		new := bundleItemsFromList(newRaw.([]interface{}))
+		removed := removedBundleItems(old, new)
+		if len(removed) > 0 {
+			if err := applyBundleItems(deletedBundleItems(removed), m, "delete dropped bundle items"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceAciRestManagedBundleApply(d, m)
+}
+
+// restGetBundleItem GETs a single bundle item's dn, retrying per
+// defaultRetryPolicy the same way resourceAciRestManagedRead retries RestGet
+// for aci_rest_managed.
+func restGetBundleItem(it bundleItem, m interface{}) (*container.Container, error) {
+	aciClient := m.(*client.Client)
+	policy := defaultRetryPolicy
+	for attempts := 0; ; attempts++ {
+		req, err := aciClient.MakeRestRequest("GET", getPath(it.Dn), nil, true)
+		if err != nil {
+			return nil, err
+		}
+		respCont, resp, err := aciClient.Do(req)
+		statusCode := statusCodeOf(resp)
+		wait := retryAfter(resp)
+		if err != nil {
+			if !shouldRetry(err, statusCode, attempts, policy) {
+				return nil, err
+			}
+			log.Printf("[ERROR] Failed to read bundle item %s: %s, retries: %v", it.Dn, err, attempts)
+			time.Sleep(backoffDelay(attempts, policy, wait))
+			continue
+		}
+		if respCont.S("imdata").Index(0).String() == "{}" {
+			return nil, nil
+		}
+		if err := client.CheckForErrors(respCont, "GET", false); err != nil {
+			return nil, err
+		}
+		return respCont, nil
+	}
+}
+
+func resourceAciRestManagedBundleRead(d *schema.ResourceData, m interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning Read", d.Id())
+
+	items := bundleItemsFromResourceData(d)
+	newItems := make([]interface{}, len(items))
+	for i, it := range items {
+		cont, err := restGetBundleItem(it, m)
+		if err != nil {
+			return err
+		}
+		if cont == nil {
+			d.SetId("")
+			return nil
+		}
+
+		newContent := make(map[string]interface{})
+		for key := range it.Content {
+			attr := cont.Search("imdata", it.ClassName, "attributes", key)
+			newContent[key] = models.StripQuotes(models.StripSquareBrackets(attr.String()))
+		}
+		newItems[i] = map[string]interface{}{
+			"dn":         it.Dn,
+			"class_name": it.ClassName,
+			"content":    newContent,
+		}
+	}
+	d.Set("item", newItems)
+
+	log.Printf("[DEBUG] %s: Read finished successfully", d.Id())
+	return nil
+}
+
+func resourceAciRestManagedBundleDelete(d *schema.ResourceData, m interface{}) error {
+	log.Printf("[DEBUG] %s: Beginning Destroy", d.Id())
+
+	items := bundleItemsFromResourceData(d)
+	if err := applyBundleItems(deletedBundleItems(items), m, "destroy bundle"); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] %s: Destroy finished successfully", d.Id())
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,87 @@
+package aci
+
+import "testing"
+
+func TestComposeBundlePayloadSetsRnForNestedItems(t *testing.T) {
+	items := []bundleItem{
+		{Dn: "uni/tn-T1", ClassName: "fvTenant", Content: map[string]interface{}{"name": "T1"}},
+		{Dn: "uni/tn-T1/ap-AP1", ClassName: "fvAp", Content: map[string]interface{}{"name": "AP1"}},
+	}
+
+	cont, err := composeBundlePayload(items)
+	if err != nil {
+		t.Fatalf("composeBundlePayload returned error: %s", err)
+	}
+
+	children := cont.S("uni", "children").Index(0).S("fvTenant", "children")
+	rn := children.Index(0).S("fvAp", "attributes", "rn").Data()
+	if rn != "ap-AP1" {
+		t.Fatalf("expected nested item to carry rn %q, got %v", "ap-AP1", rn)
+	}
+
+	// The nested item must not also carry an absolute dn; it is addressed
+	// relative to its parent.
+	if dn := children.Index(0).S("fvAp", "attributes", "dn").Data(); dn != nil {
+		t.Fatalf("expected nested item to have no dn attribute, got %v", dn)
+	}
+}
+
+func TestComposeBundlePayloadSetsDnForItemsWithoutParentInBundle(t *testing.T) {
+	// T1 is not part of this bundle (e.g. it already exists in APIC), so
+	// AP1 must be addressed by its absolute dn rather than nested under uni
+	// as if uni were its parent.
+	items := []bundleItem{
+		{Dn: "uni/tn-T1/ap-AP1", ClassName: "fvAp", Content: map[string]interface{}{"name": "AP1"}},
+	}
+
+	cont, err := composeBundlePayload(items)
+	if err != nil {
+		t.Fatalf("composeBundlePayload returned error: %s", err)
+	}
+
+	dn := cont.S("uni", "children").Index(0).S("fvAp", "attributes", "dn").Data()
+	if dn != "uni/tn-T1/ap-AP1" {
+		t.Fatalf("expected root item to carry absolute dn %q, got %v", "uni/tn-T1/ap-AP1", dn)
+	}
+}
+
+func TestComposeBundlePayloadFallsBackToDnWhenIntermediateAncestorMissing(t *testing.T) {
+	// AP1 (the direct parent of EPG1) is not part of this bundle, only its
+	// grandparent tenant T1 is. Nesting EPG1's rn "epg-EPG1" directly under
+	// T1's children would be wrong, since EPG1 is not actually T1's child, so
+	// EPG1 must fall back to its own absolute dn instead.
+	items := []bundleItem{
+		{Dn: "uni/tn-T1", ClassName: "fvTenant", Content: map[string]interface{}{"name": "T1"}},
+		{Dn: "uni/tn-T1/ap-AP1/epg-EPG1", ClassName: "fvAEPg", Content: map[string]interface{}{"name": "EPG1"}},
+	}
+
+	cont, err := composeBundlePayload(items)
+	if err != nil {
+		t.Fatalf("composeBundlePayload returned error: %s", err)
+	}
+
+	roots := cont.S("uni", "children")
+	if dn := roots.Index(1).S("fvAEPg", "attributes", "dn").Data(); dn != "uni/tn-T1/ap-AP1/epg-EPG1" {
+		t.Fatalf("expected fvAEPg item to carry absolute dn %q, got %v", "uni/tn-T1/ap-AP1/epg-EPG1", dn)
+	}
+
+	tenantChildren := roots.Index(0).S("fvTenant", "children")
+	if len(tenantChildren.Children()) != 0 {
+		t.Fatalf("expected tenant to have no nested children, got %d", len(tenantChildren.Children()))
+	}
+}
+
+func TestRemovedBundleItems(t *testing.T) {
+	old := []bundleItem{
+		{Dn: "uni/tn-T1", ClassName: "fvTenant"},
+		{Dn: "uni/tn-T1/ap-AP1", ClassName: "fvAp"},
+	}
+	new := []bundleItem{
+		{Dn: "uni/tn-T1", ClassName: "fvTenant"},
+	}
+
+	removed := removedBundleItems(old, new)
+	if len(removed) != 1 || removed[0].Dn != "uni/tn-T1/ap-AP1" {
+		t.Fatalf("expected only uni/tn-T1/ap-AP1 to be reported removed, got %+v", removed)
+	}
+}
@@ -0,0 +1,94 @@
+package aci
+
+import (
+	"testing"
+
+	"github.com/ciscoecosystem/aci-go-client/container"
+)
+
+func TestComposeChildrenPayloadSetsRn(t *testing.T) {
+	cont, err := preparePayload("fvTenant", map[string]interface{}{"name": "T1"})
+	if err != nil {
+		t.Fatalf("preparePayload returned error: %s", err)
+	}
+
+	children := []restManagedChild{
+		{Rn: "ap-AP1", ClassName: "fvAp", Content: map[string]interface{}{"name": "AP1"}},
+	}
+	if err := composeChildrenPayload(cont, "fvTenant", children); err != nil {
+		t.Fatalf("composeChildrenPayload returned error: %s", err)
+	}
+
+	rn := cont.S("fvTenant", "children").Index(0).S("fvAp", "attributes", "rn").Data()
+	if rn != "ap-AP1" {
+		t.Fatalf("expected child rn %q, got %v", "ap-AP1", rn)
+	}
+}
+
+func TestReconcileChildAppliesStateIgnoreAttributes(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"imdata":[{"fvTenant":{"children":[{"fvAp":{"attributes":{"rn":"ap-AP1","name":"AP1-from-apic"}}}]}}]}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+
+	ch := restManagedChild{
+		Rn:          "ap-AP1",
+		ClassName:   "fvAp",
+		Content:     map[string]interface{}{"name": "AP1-configured"},
+		IgnoreAttrs: []string{"name"},
+	}
+
+	content := reconcileChild(cont, "fvTenant", ch)
+	if content["name"] != "AP1-configured" {
+		t.Fatalf("expected ignored attribute to keep its configured value, got %v", content["name"])
+	}
+}
+
+func TestReconcileChildReconcilesFromApicResponse(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"imdata":[{"fvTenant":{"children":[{"fvAp":{"attributes":{"rn":"ap-AP1","name":"AP1-from-apic"}}}]}}]}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+
+	ch := restManagedChild{
+		Rn:        "ap-AP1",
+		ClassName: "fvAp",
+		Content:   map[string]interface{}{"name": "AP1-configured"},
+	}
+
+	content := reconcileChild(cont, "fvTenant", ch)
+	if content["name"] != "AP1-from-apic" {
+		t.Fatalf("expected content to reflect what APIC persisted, got %v", content["name"])
+	}
+}
+
+func TestReconcileChildIgnoresUndeclaredChildren(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"imdata":[{"fvTenant":{"children":[{"fvAp":{"attributes":{"rn":"ap-OTHER","name":"other"}}}]}}]}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+
+	ch := restManagedChild{
+		Rn:        "ap-AP1",
+		ClassName: "fvAp",
+		Content:   map[string]interface{}{"name": "AP1-configured"},
+	}
+
+	content := reconcileChild(cont, "fvTenant", ch)
+	if len(content) != 0 {
+		t.Fatalf("expected no reconciled content for a child not present in the response, got %+v", content)
+	}
+}
+
+func TestRemovedChildrenRespectsDeleteOnRemoval(t *testing.T) {
+	old := []restManagedChild{
+		{Rn: "ap-AP1", ClassName: "fvAp", DeleteOnRemoval: true},
+		{Rn: "ap-AP2", ClassName: "fvAp", DeleteOnRemoval: false},
+	}
+	var new []restManagedChild
+
+	removed := removedChildren(old, new)
+	if len(removed) != 1 || removed[0].Rn != "ap-AP1" {
+		t.Fatalf("expected only ap-AP1 to be reported removed, got %+v", removed)
+	}
+}
@@ -0,0 +1,124 @@
+package aci
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how resourceAciRestManaged retries failed APIC requests.
+// It is populated from the provider's max_retries, retry_min_delay, retry_max_delay
+// and retryable_status_codes arguments during provider configuration.
+type RetryPolicy struct {
+	MaxRetries           int
+	MinDelay             time.Duration
+	MaxDelay             time.Duration
+	RetryableStatusCodes []int
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxRetries:           2,
+	MinDelay:             1 * time.Second,
+	MaxDelay:             30 * time.Second,
+	RetryableStatusCodes: []int{http.StatusTooManyRequests, 500, 502, 503, 504},
+}
+
+// SetRetryPolicy overrides the package-wide retry policy used by resourceAciRestManaged.
+// It is called once from the provider's ConfigureFunc.
+func SetRetryPolicy(p RetryPolicy) {
+	defaultRetryPolicy = p
+}
+
+// backoffDelay returns how long to sleep before the given zero-based retry
+// attempt. When APIC returned a Retry-After header, retryAfter is the parsed
+// duration and takes precedence over the jittered calculation, since the
+// server told us exactly how long it wants us to wait (e.g. on a 429).
+// Otherwise it returns a full-jitter exponential backoff delay capped at
+// policy.MaxDelay.
+func backoffDelay(attempt int, policy RetryPolicy, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	cap := policy.MaxDelay
+	base := policy.MinDelay
+	if base <= 0 {
+		base = 1 * time.Second
+	}
+	if cap <= 0 {
+		cap = 30 * time.Second
+	}
+
+	max := base << uint(attempt)
+	if max <= 0 || max > cap {
+		max = cap
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// retryAfter parses the Retry-After header from an APIC response, supporting
+// both the delay-in-seconds and HTTP-date forms. It returns 0 if resp is nil
+// or the header is absent or unparseable.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// isRetryableStatusCode reports whether statusCode is configured as retryable.
+func isRetryableStatusCode(statusCode int, policy RetryPolicy) bool {
+	if statusCode == 0 {
+		return false
+	}
+	for _, code := range policy.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableError reports whether err looks like a transient network error,
+// such as a dropped connection or a premature EOF, that is worth retrying
+// even though no HTTP status code was returned.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "EOF") ||
+		strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "timeout")
+}
+
+// shouldRetry decides whether a failed request should be retried given the
+// configured policy, the attempt number (zero-based) and the HTTP status code
+// of the response, if any.
+func shouldRetry(err error, statusCode int, attempt int, policy RetryPolicy) bool {
+	if attempt >= policy.MaxRetries {
+		return false
+	}
+	if isRetryableStatusCode(statusCode, policy) {
+		return true
+	}
+	return isRetryableError(err)
+}
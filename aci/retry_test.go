@@ -0,0 +1,45 @@
+package aci
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelayHonorsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, MinDelay: time.Second, MaxDelay: 30 * time.Second}
+
+	got := backoffDelay(0, policy, 5*time.Second)
+	if got != 5*time.Second {
+		t.Fatalf("expected Retry-After to take precedence, got %s", got)
+	}
+}
+
+func TestBackoffDelayBounds(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, MinDelay: time.Second, MaxDelay: 4 * time.Second}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		got := backoffDelay(attempt, policy, 0)
+		if got < 0 || got > policy.MaxDelay {
+			t.Fatalf("attempt %d: backoffDelay = %s, want within [0, %s]", attempt, got, policy.MaxDelay)
+		}
+	}
+}
+
+func TestRetryAfterParsesSeconds(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	got := retryAfter(resp)
+	if got != 3*time.Second {
+		t.Fatalf("retryAfter = %s, want 3s", got)
+	}
+}
+
+func TestRetryAfterMissingHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	if got := retryAfter(resp); got != 0 {
+		t.Fatalf("retryAfter = %s, want 0", got)
+	}
+	if got := retryAfter(nil); got != 0 {
+		t.Fatalf("retryAfter(nil) = %s, want 0", got)
+	}
+}
@@ -0,0 +1,241 @@
+package aci
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ciscoecosystem/aci-go-client/client"
+	"github.com/ciscoecosystem/aci-go-client/container"
+	"github.com/ciscoecosystem/aci-go-client/models"
+	"github.com/gorilla/websocket"
+)
+
+// subscriptionRefreshInterval must stay comfortably under APIC's 60 second
+// subscription timeout.
+const subscriptionRefreshInterval = 50 * time.Second
+
+// websocketModeEnabled toggles resourceAciRestManagedRead between issuing a
+// REST GET per refresh and consulting the shared subscription cache. It is
+// set once from the provider's ConfigureFunc based on a top-level
+// subscription_mode argument.
+var websocketModeEnabled bool
+
+// SetWebsocketMode overrides the package-wide websocket subscription mode
+// used by resourceAciRestManaged.
+func SetWebsocketMode(enabled bool) {
+	websocketModeEnabled = enabled
+}
+
+// subscriptionManager keeps a single APIC websocket connection open and
+// caches the latest known state for every dn subscribed to through it, so
+// that refreshing thousands of aci_rest_managed resources does not require
+// one REST GET per resource.
+type subscriptionManager struct {
+	aciClient *client.Client
+
+	mu           sync.RWMutex
+	conn         *websocket.Conn
+	subscription map[string]string // dn -> subscription id
+	cache        map[string]*container.Container
+
+	startOnce sync.Once
+	startErr  error
+}
+
+var (
+	subscriptionManagers   = map[*client.Client]*subscriptionManager{}
+	subscriptionManagersMu sync.Mutex
+)
+
+// getSubscriptionManager returns the subscription manager for this provider's
+// client, creating it on first use. The websocket itself is opened by the
+// first read().
+func getSubscriptionManager(aciClient *client.Client) *subscriptionManager {
+	subscriptionManagersMu.Lock()
+	defer subscriptionManagersMu.Unlock()
+
+	sm, ok := subscriptionManagers[aciClient]
+	if !ok {
+		sm = &subscriptionManager{
+			aciClient:    aciClient,
+			subscription: make(map[string]string),
+			cache:        make(map[string]*container.Container),
+		}
+		subscriptionManagers[aciClient] = sm
+	}
+	return sm
+}
+
+func (sm *subscriptionManager) start() error {
+	sm.startOnce.Do(func() {
+		conn, err := sm.aciClient.OpenWebSocket(false)
+		if err != nil {
+			sm.startErr = err
+			return
+		}
+		sm.conn = conn
+		go sm.readLoop()
+		go sm.refreshLoop()
+	})
+	return sm.startErr
+}
+
+// eventAttributes pulls the "attributes" object out of a push notification's
+// first imdata entry. APIC wraps it under the class name, e.g.
+// imdata[0].fvTenant.attributes, the same way a GET response does, so this
+// mirrors how getAciRestManaged and dataSourceAciRestManagedRead read class
+// data instead of reaching straight for "attributes" off imdata[0].
+func eventAttributes(cont *container.Container) *container.Container {
+	classes, err := cont.S("imdata").Index(0).ChildrenMap()
+	if err != nil {
+		return nil
+	}
+	for _, classCont := range classes {
+		if attrs := classCont.S("attributes"); attrs.Data() != nil {
+			return attrs
+		}
+	}
+	return nil
+}
+
+// readLoop consumes push notifications from APIC and updates the cache for
+// whichever dn each event belongs to. A notification reporting the MO as
+// deleted evicts it from the cache and drops its subscription instead of
+// caching the tombstone, so the next read() call re-subscribes to the dn and
+// re-learns its current state: an empty imdata from APIC is cached as nil,
+// which read() reports as a dn that does not exist.
+func (sm *subscriptionManager) readLoop() {
+	for {
+		_, payload, err := sm.conn.ReadMessage()
+		if err != nil {
+			log.Printf("[ERROR] aci_rest_managed subscription websocket closed: %s", err)
+			return
+		}
+
+		cont, err := container.ParseJSON(payload)
+		if err != nil {
+			log.Printf("[ERROR] aci_rest_managed failed to parse subscription event: %s", err)
+			continue
+		}
+
+		attrs := eventAttributes(cont)
+		if attrs == nil {
+			continue
+		}
+
+		dn := models.StripQuotes(attrs.S("dn").String())
+		if dn == "" {
+			continue
+		}
+
+		sm.mu.Lock()
+		if models.StripQuotes(attrs.S("status").String()) == "deleted" {
+			delete(sm.cache, dn)
+			delete(sm.subscription, dn)
+		} else {
+			sm.cache[dn] = cont
+		}
+		sm.mu.Unlock()
+	}
+}
+
+// refreshLoop keeps every active subscription alive, well before APIC's
+// 60 second subscription timeout.
+func (sm *subscriptionManager) refreshLoop() {
+	ticker := time.NewTicker(subscriptionRefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		sm.mu.RLock()
+		ids := make([]string, 0, len(sm.subscription))
+		for _, id := range sm.subscription {
+			ids = append(ids, id)
+		}
+		sm.mu.RUnlock()
+
+		for _, id := range ids {
+			req, err := sm.aciClient.MakeRestRequest("GET", "/api/subscriptionRefresh.json?id="+id, nil, true)
+			if err != nil {
+				log.Printf("[ERROR] aci_rest_managed failed to build subscription refresh request: %s", err)
+				continue
+			}
+			if _, _, err := sm.aciClient.Do(req); err != nil {
+				log.Printf("[ERROR] aci_rest_managed failed to refresh subscription %s: %s", id, err)
+			}
+		}
+	}
+}
+
+// subscribe registers a subscription for dn if one does not already exist.
+func (sm *subscriptionManager) subscribe(dn string) error {
+	sm.mu.RLock()
+	_, ok := sm.subscription[dn]
+	sm.mu.RUnlock()
+	if ok {
+		return nil
+	}
+
+	path := getPath(dn) + "?subscription=yes"
+	req, err := sm.aciClient.MakeRestRequest("GET", path, nil, true)
+	if err != nil {
+		return err
+	}
+	cont, _, err := sm.aciClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	// Mirror RestGet's empty-imdata "object does not exist" check: cache a
+	// nil container instead of an empty one so read() can tell its caller to
+	// treat this as a deleted object, the same way the REST fallback path
+	// does.
+	if cont.S("imdata").Index(0).String() == "{}" {
+		subID := models.StripQuotes(cont.S("subscriptionId").String())
+		sm.mu.Lock()
+		if subID != "" {
+			sm.subscription[dn] = subID
+		}
+		sm.cache[dn] = nil
+		sm.mu.Unlock()
+		return nil
+	}
+
+	err = client.CheckForErrors(cont, "GET", false)
+	if err != nil {
+		return err
+	}
+
+	subID := models.StripQuotes(cont.S("subscriptionId").String())
+	if subID == "" {
+		return fmt.Errorf("apic did not return a subscriptionId for %s", dn)
+	}
+
+	sm.mu.Lock()
+	sm.subscription[dn] = subID
+	sm.cache[dn] = cont
+	sm.mu.Unlock()
+	return nil
+}
+
+// read returns the cached container for dn, subscribing to it first if
+// necessary. A nil container with a nil error means dn does not exist,
+// mirroring RestGet's empty-imdata convention; callers should fall back to a
+// plain RestGet if it returns a non-nil error.
+func (sm *subscriptionManager) read(dn string) (*container.Container, error) {
+	if err := sm.start(); err != nil {
+		return nil, err
+	}
+	if err := sm.subscribe(dn); err != nil {
+		return nil, err
+	}
+
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	cont, ok := sm.cache[dn]
+	if !ok {
+		return nil, fmt.Errorf("no cached state for %s", dn)
+	}
+	return cont, nil
+}
@@ -0,0 +1,32 @@
+package aci
+
+import (
+	"testing"
+
+	"github.com/ciscoecosystem/aci-go-client/container"
+)
+
+func TestEventAttributesReadsUnderClassKey(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"imdata":[{"fvTenant":{"attributes":{"dn":"uni/tn-T1","status":"modified"}}}]}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+
+	attrs := eventAttributes(cont)
+	if attrs == nil {
+		t.Fatal("eventAttributes returned nil")
+	}
+	if dn := attrs.S("dn").Data(); dn != "uni/tn-T1" {
+		t.Fatalf("expected dn uni/tn-T1, got %v", dn)
+	}
+}
+
+func TestEventAttributesNoClassKey(t *testing.T) {
+	cont, err := container.ParseJSON([]byte(`{"imdata":[{}]}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+	if attrs := eventAttributes(cont); attrs != nil {
+		t.Fatalf("expected nil attrs for empty event, got %v", attrs)
+	}
+}